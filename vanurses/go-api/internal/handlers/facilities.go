@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"vanurses-api/internal/repository"
@@ -17,38 +18,43 @@ func NewFacilityHandler(repo *repository.FacilityRepository) *FacilityHandler {
 	return &FacilityHandler{repo: repo}
 }
 
-// GetFacilities returns paginated list of facilities
+// GetFacilities returns a cursor-paginated list of facilities. The legacy
+// `limit`/`offset` parameters are still honored when `after` is absent, for
+// a deprecation window.
 func (h *FacilityHandler) GetFacilities(c *gin.Context) {
 	// Parse query parameters
 	region := c.Query("region")
 	systemName := c.Query("system")
 	facilityType := c.Query("type")
+	after := c.Query("after")
 
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	first, _ := strconv.Atoi(c.DefaultQuery("first", c.DefaultQuery("limit", "50")))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
 
-	// Clamp limit
-	if limit > 100 {
-		limit = 100
+	// Clamp first
+	if first > 100 {
+		first = 100
 	}
-	if limit < 1 {
-		limit = 50
+	if first < 1 {
+		first = 50
 	}
 
-	facilities, total, err := h.repo.GetFacilities(region, systemName, facilityType, limit, offset)
+	page := repository.PageParams{First: first, After: after, Offset: offset}
+
+	edges, total, pageInfo, err := h.repo.GetFacilities(region, systemName, facilityType, page)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch facilities", err.Error())
 		return
 	}
 
-	utils.PaginatedResponse(c, facilities, total, limit, offset)
+	utils.ConnectionResponse(c, edges, total, pageInfo)
 }
 
 // GetFacility returns a single facility by ID with all scores
 func (h *FacilityHandler) GetFacility(c *gin.Context) {
 	id := c.Param("id")
 
-	facility, err := h.repo.GetFacilityByID(id)
+	facility, err := h.repo.GetFacilityByID(c.Request.Context(), id)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch facility", err.Error())
 		return
@@ -59,55 +65,116 @@ func (h *FacilityHandler) GetFacility(c *gin.Context) {
 		return
 	}
 
-	utils.SuccessResponse(c, facility)
+	utils.CachedResponse(c, facility)
 }
 
-// GetFacilitiesWithScores returns facilities with all 9 index scores
+// GetFacilitiesWithScores returns a cursor-paginated list of facilities with
+// all 9 index scores. The legacy `limit`/`offset` parameters are still
+// honored when `after` is absent, for a deprecation window.
 func (h *FacilityHandler) GetFacilitiesWithScores(c *gin.Context) {
 	region := c.Query("region")
+	after := c.Query("after")
 
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	first, _ := strconv.Atoi(c.DefaultQuery("first", c.DefaultQuery("limit", "50")))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
 
-	if limit > 100 {
-		limit = 100
+	if first > 100 {
+		first = 100
 	}
-	if limit < 1 {
-		limit = 50
+	if first < 1 {
+		first = 50
 	}
 
-	facilities, total, err := h.repo.GetFacilitiesWithScores(region, limit, offset)
+	page := repository.PageParams{First: first, After: after, Offset: offset}
+
+	edges, total, pageInfo, err := h.repo.GetFacilitiesWithScores(region, page)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch facilities", err.Error())
 		return
 	}
 
-	utils.PaginatedResponse(c, facilities, total, limit, offset)
+	utils.ConnectionResponse(c, edges, total, pageInfo)
+}
+
+// SearchFacilities handles full-text and geospatial discovery search over
+// facilities, combining ranked text search, radius search, multi-value
+// region/type filters and a minimum OFS score filter.
+func (h *FacilityHandler) SearchFacilities(c *gin.Context) {
+	params := repository.SearchParams{
+		Query: c.Query("q"),
+	}
+
+	if lat := c.Query("lat"); lat != "" {
+		if v, err := strconv.ParseFloat(lat, 64); err == nil {
+			params.Lat = &v
+		}
+	}
+	if lng := c.Query("lng"); lng != "" {
+		if v, err := strconv.ParseFloat(lng, 64); err == nil {
+			params.Lng = &v
+		}
+	}
+	if radius := c.Query("radius_km"); radius != "" {
+		if v, err := strconv.ParseFloat(radius, 64); err == nil {
+			params.RadiusKm = &v
+		}
+	}
+	if regions := c.Query("regions"); regions != "" {
+		params.Regions = strings.Split(regions, ",")
+	}
+	if types := c.Query("types"); types != "" {
+		params.Types = strings.Split(types, ",")
+	}
+	if minOFS := c.Query("min_ofs"); minOFS != "" {
+		if v, err := strconv.ParseFloat(minOFS, 64); err == nil {
+			params.MinOFS = &v
+		}
+	}
+
+	first, _ := strconv.Atoi(c.DefaultQuery("first", c.DefaultQuery("limit", "50")))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if first > 100 {
+		first = 100
+	}
+	if first < 1 {
+		first = 50
+	}
+	params.Page = repository.PageParams{First: first, After: c.Query("after"), Offset: offset}
+
+	edges, total, pageInfo, err := h.repo.SearchFacilities(params)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to search facilities", err.Error())
+		return
+	}
+
+	utils.ConnectionResponse(c, edges, total, pageInfo)
 }
 
-// GetRegions returns all regions
+// GetRegions returns all regions. Cached, and served with an ETag so
+// clients can skip re-downloading an unchanged list.
 func (h *FacilityHandler) GetRegions(c *gin.Context) {
-	regions, err := h.repo.GetRegions()
+	regions, err := h.repo.GetRegions(c.Request.Context())
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch regions", err.Error())
 		return
 	}
 
-	utils.SuccessResponse(c, gin.H{
+	utils.CachedResponse(c, gin.H{
 		"regions": regions,
 		"count":   len(regions),
 	})
 }
 
-// GetSystems returns all hospital systems
+// GetSystems returns all hospital systems. Cached, and served with an ETag
+// so clients can skip re-downloading an unchanged list.
 func (h *FacilityHandler) GetSystems(c *gin.Context) {
-	systems, err := h.repo.GetSystems()
+	systems, err := h.repo.GetSystems(c.Request.Context())
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch systems", err.Error())
 		return
 	}
 
-	utils.SuccessResponse(c, gin.H{
+	utils.CachedResponse(c, gin.H{
 		"systems": systems,
 		"count":   len(systems),
 	})
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"vanurses-api/internal/jobs"
+	"vanurses-api/internal/repository"
+	"vanurses-api/internal/service"
+	"vanurses-api/pkg/domain"
+	"vanurses-api/pkg/utils"
+)
+
+// ScoreHandler exposes the admin-only write path for ingesting and
+// recomputing index scores.
+type ScoreHandler struct {
+	scores *repository.ScoreRepository
+	calc   *service.ScoreCalculator
+	worker *jobs.RecomputeWorker
+}
+
+func NewScoreHandler(scores *repository.ScoreRepository, calc *service.ScoreCalculator, worker *jobs.RecomputeWorker) *ScoreHandler {
+	return &ScoreHandler{scores: scores, calc: calc, worker: worker}
+}
+
+type upsertScoresRequest struct {
+	PCIScore  *float64 `json:"pciScore"`
+	ERIScore  *float64 `json:"eriScore"`
+	PEIScore  *float64 `json:"peiScore"`
+	FSIScore  *float64 `json:"fsiScore"`
+	LSSIScore *float64 `json:"lssiScore"`
+	ALIScore  *float64 `json:"aliScore"`
+	CSIScore  *float64 `json:"csiScore"`
+	QLIScore  *float64 `json:"qliScore"`
+	CCIScore  *float64 `json:"cciScore"`
+}
+
+func nullFloat64(v *float64) domain.NullFloat64 {
+	if v == nil {
+		return domain.NullFloat64{}
+	}
+	return domain.NullFloat64{NullFloat64: sql.NullFloat64{Float64: *v, Valid: true}}
+}
+
+// UpsertScores handles POST /admin/facilities/:id/scores.
+func (h *ScoreHandler) UpsertScores(c *gin.Context) {
+	facilityID := c.Param("id")
+
+	var body upsertScoresRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	scores := domain.FacilityScores{
+		PCIScore:  nullFloat64(body.PCIScore),
+		ERIScore:  nullFloat64(body.ERIScore),
+		PEIScore:  nullFloat64(body.PEIScore),
+		FSIScore:  nullFloat64(body.FSIScore),
+		LSSIScore: nullFloat64(body.LSSIScore),
+		ALIScore:  nullFloat64(body.ALIScore),
+		CSIScore:  nullFloat64(body.CSIScore),
+		QLIScore:  nullFloat64(body.QLIScore),
+		CCIScore:  nullFloat64(body.CCIScore),
+	}
+
+	if err := h.scores.UpsertScores(c.Request.Context(), facilityID, scores); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to upsert scores", err.Error())
+		return
+	}
+	h.worker.Enqueue(facilityID)
+
+	utils.SuccessResponse(c, gin.H{"facilityId": facilityID})
+}
+
+// BulkUpsertScores handles POST /admin/scores/bulk, ingesting a multipart
+// CSV upload of component scores.
+func (h *ScoreHandler) BulkUpsertScores(c *gin.Context) {
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Missing CSV file", err.Error())
+		return
+	}
+	defer file.Close()
+
+	rowsUpserted, err := h.scores.BulkUpsertScoresFromCSV(c.Request.Context(), file)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to bulk upsert scores", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{"rowsUpserted": rowsUpserted})
+}
+
+// RecomputeScores handles POST /admin/scores/recompute, recomputing OFS for
+// every scored facility (optionally narrowed to a region).
+func (h *ScoreHandler) RecomputeScores(c *gin.Context) {
+	region := c.Query("region")
+
+	count, err := h.calc.RecomputeRegion(c.Request.Context(), region)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to recompute scores", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{"recomputed": count})
+}
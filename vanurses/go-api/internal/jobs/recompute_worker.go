@@ -0,0 +1,61 @@
+package jobs
+
+import (
+	"context"
+	"log"
+
+	"github.com/jmoiron/sqlx"
+	"vanurses-api/internal/service"
+)
+
+// RecomputeWorker drains an async queue of facility ids whose component
+// scores changed and recomputes OFS for each. The UpsertScores handler
+// enqueues every facility it writes; since UpsertScores already recomputes
+// OFS transactionally as part of its own write, this second recompute is a
+// belt-and-suspenders pass - it is not on the critical path of any request.
+type RecomputeWorker struct {
+	db    *sqlx.DB
+	calc  *service.ScoreCalculator
+	queue chan string
+}
+
+func NewRecomputeWorker(db *sqlx.DB, calc *service.ScoreCalculator, queueSize int) *RecomputeWorker {
+	return &RecomputeWorker{db: db, calc: calc, queue: make(chan string, queueSize)}
+}
+
+// Enqueue schedules facilityID for recomputation. It never blocks: if the
+// queue is full the request is dropped, since the next score write for that
+// facility will recompute it anyway.
+func (w *RecomputeWorker) Enqueue(facilityID string) {
+	select {
+	case w.queue <- facilityID:
+	default:
+		log.Printf("recompute worker: queue full, dropping facility %s", facilityID)
+	}
+}
+
+// Run processes the queue until ctx is canceled.
+func (w *RecomputeWorker) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case facilityID := <-w.queue:
+			if err := w.recomputeOne(ctx, facilityID); err != nil {
+				log.Printf("recompute worker: facility %s: %v", facilityID, err)
+			}
+		}
+	}
+}
+
+func (w *RecomputeWorker) recomputeOne(ctx context.Context, facilityID string) error {
+	tx, err := w.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := w.calc.RecomputeFacility(ctx, tx, facilityID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"vanurses-api/pkg/utils"
+)
+
+// RequireAdmin guards the /admin routes with a shared-secret token: the
+// X-Admin-Token request header must match the ADMIN_API_TOKEN environment
+// variable, and every request is rejected while that variable is unset.
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		expected := os.Getenv("ADMIN_API_TOKEN")
+		provided := c.GetHeader("X-Admin-Token")
+
+		if expected == "" || subtle.ConstantTimeCompare([]byte(expected), []byte(provided)) != 1 {
+			utils.ErrorResponse(c, http.StatusForbidden, "Admin access required", "")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func runRequireAdmin(t *testing.T, token, header string) *httptest.ResponseRecorder {
+	t.Helper()
+	t.Setenv("ADMIN_API_TOKEN", token)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(http.MethodPost, "/admin/scores/recompute", nil)
+	if header != "" {
+		req.Header.Set("X-Admin-Token", header)
+	}
+	c.Request = req
+
+	RequireAdmin()(c)
+	return w
+}
+
+func TestRequireAdminRejectsMissingToken(t *testing.T) {
+	w := runRequireAdmin(t, "secret", "")
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
+
+func TestRequireAdminRejectsWrongToken(t *testing.T) {
+	w := runRequireAdmin(t, "secret", "wrong")
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
+
+func TestRequireAdminRejectsWhenUnconfigured(t *testing.T) {
+	w := runRequireAdmin(t, "", "anything")
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
+
+func TestRequireAdminAcceptsCorrectToken(t *testing.T) {
+	w := runRequireAdmin(t, "secret", "secret")
+	if w.Code != http.StatusOK && w.Code != 0 {
+		t.Errorf("status = %d, want request to pass through (no abort)", w.Code)
+	}
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// cacheSchemaVersion is embedded in every cache key so that a deploy which
+// changes the shape of a cached value invalidates old entries instead of
+// serving them to code that no longer expects that shape.
+const cacheSchemaVersion = "v1"
+
+// Cache is the abstraction reference-data reads are wrapped in. Both
+// implementations store pre-encoded JSON bytes so callers don't need to
+// know which backend they're talking to.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Invalidate(ctx context.Context, key string) error
+}
+
+func cacheKey(parts ...string) string {
+	key := cacheSchemaVersion
+	for _, p := range parts {
+		key += ":" + p
+	}
+	return key
+}
+
+// cachedFetch loads dest from cache under key, falling back to load on a
+// miss. Concurrent misses for the same key are collapsed with sf so only
+// one caller actually runs load.
+func cachedFetch(ctx context.Context, cache Cache, sf *singleflight.Group, key string, ttl time.Duration, dest interface{}, load func() (interface{}, error)) error {
+	if raw, ok, err := cache.Get(ctx, key); err == nil && ok {
+		if err := json.Unmarshal(raw, dest); err == nil {
+			return nil
+		}
+	}
+
+	value, err, _ := sf.Do(key, func() (interface{}, error) {
+		if raw, ok, err := cache.Get(ctx, key); err == nil && ok {
+			return raw, nil
+		}
+
+		loaded, err := load()
+		if err != nil {
+			return nil, err
+		}
+		raw, err := json.Marshal(loaded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode cache value for %s: %w", key, err)
+		}
+		if err := cache.Set(ctx, key, raw, ttl); err != nil {
+			return nil, err
+		}
+		return raw, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(value.([]byte), dest)
+}
+
+// memoryCacheSweepInterval controls how often expired entries are reaped
+// from a MemoryCache in the background. Without this, a key that's only
+// ever Get and never Set again (e.g. GetFacilityByID for a facility nobody
+// else looks up) would sit in the map forever once expired.
+const memoryCacheSweepInterval = 5 * time.Minute
+
+// MemoryCache is an in-process TTL cache. It's appropriate for single-node
+// deployments or as an L1 in front of RedisCache.
+type MemoryCache struct {
+	mu      sync.RWMutex
+	entries map[string]memoryCacheEntry
+}
+
+type memoryCacheEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+func NewMemoryCache() *MemoryCache {
+	c := &MemoryCache{entries: make(map[string]memoryCacheEntry)}
+	go c.sweepLoop()
+	return c
+}
+
+// sweepLoop periodically evicts expired entries so the map doesn't grow
+// unbounded over the process lifetime. It runs for as long as the process
+// does; MemoryCache is a long-lived singleton, not something callers tear
+// down.
+func (c *MemoryCache) sweepLoop() {
+	ticker := time.NewTicker(memoryCacheSweepInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		c.sweep()
+	}
+}
+
+func (c *MemoryCache) sweep() {
+	now := time.Now()
+	c.mu.Lock()
+	for key, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+	c.mu.Unlock()
+}
+
+func (c *MemoryCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+func (c *MemoryCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	c.entries[key] = memoryCacheEntry{value: value, expiresAt: time.Now().Add(ttl)}
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *MemoryCache) Invalidate(_ context.Context, key string) error {
+	c.mu.Lock()
+	delete(c.entries, key)
+	c.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryCacheSweepEvictsExpiredEntries(t *testing.T) {
+	c := NewMemoryCache()
+	ctx := context.Background()
+
+	if err := c.Set(ctx, "expired", []byte("x"), -time.Second); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+	if err := c.Set(ctx, "fresh", []byte("y"), time.Hour); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+
+	c.sweep()
+
+	c.mu.RLock()
+	_, expiredStillPresent := c.entries["expired"]
+	_, freshStillPresent := c.entries["fresh"]
+	c.mu.RUnlock()
+
+	if expiredStillPresent {
+		t.Error("sweep left an expired entry in the map")
+	}
+	if !freshStillPresent {
+		t.Error("sweep evicted a non-expired entry")
+	}
+}
+
+func TestMemoryCacheGetMissAfterExpiry(t *testing.T) {
+	c := NewMemoryCache()
+	ctx := context.Background()
+
+	if err := c.Set(ctx, "k", []byte("v"), -time.Second); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+
+	if _, ok, err := c.Get(ctx, "k"); err != nil || ok {
+		t.Errorf("Get on expired key = (ok=%v, err=%v), want (false, nil)", ok, err)
+	}
+}
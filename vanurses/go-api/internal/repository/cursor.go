@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"vanurses-api/pkg/domain"
+)
+
+// facilityCursor is the keyset position for the (name, id) ordering used by
+// GetFacilities.
+type facilityCursor struct {
+	Name string `json:"n"`
+	ID   string `json:"i"`
+}
+
+// scoredFacilityCursor is the keyset position for the (ofs_score, name, id)
+// ordering used by GetFacilitiesWithScores.
+type scoredFacilityCursor struct {
+	OFSScore *float64 `json:"o"`
+	Name     string   `json:"n"`
+	ID       string   `json:"i"`
+}
+
+func encodeCursor(v interface{}) (string, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.URLEncoding.EncodeToString(raw), nil
+}
+
+func decodeFacilityCursor(cursor string) (facilityCursor, error) {
+	var fc facilityCursor
+	raw, err := base64.URLEncoding.DecodeString(cursor)
+	if err != nil {
+		return fc, fmt.Errorf("invalid cursor: %w", err)
+	}
+	if err := json.Unmarshal(raw, &fc); err != nil {
+		return fc, fmt.Errorf("invalid cursor: %w", err)
+	}
+	return fc, nil
+}
+
+func decodeScoredFacilityCursor(cursor string) (scoredFacilityCursor, error) {
+	var sc scoredFacilityCursor
+	raw, err := base64.URLEncoding.DecodeString(cursor)
+	if err != nil {
+		return sc, fmt.Errorf("invalid cursor: %w", err)
+	}
+	if err := json.Unmarshal(raw, &sc); err != nil {
+		return sc, fmt.Errorf("invalid cursor: %w", err)
+	}
+	return sc, nil
+}
+
+func decodeSearchCursor(cursor string) (searchCursor, error) {
+	var sc searchCursor
+	raw, err := base64.URLEncoding.DecodeString(cursor)
+	if err != nil {
+		return sc, fmt.Errorf("invalid cursor: %w", err)
+	}
+	if err := json.Unmarshal(raw, &sc); err != nil {
+		return sc, fmt.Errorf("invalid cursor: %w", err)
+	}
+	return sc, nil
+}
+
+// nullFloat64Ptr converts a domain.NullFloat64 to *float64 for cursor
+// encoding, where an unset score is represented as a nil pointer.
+func nullFloat64Ptr(n domain.NullFloat64) *float64 {
+	if !n.Valid {
+		return nil
+	}
+	f := n.Float64
+	return &f
+}
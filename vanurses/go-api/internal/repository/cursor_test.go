@@ -0,0 +1,61 @@
+package repository
+
+import "testing"
+
+func TestFacilityCursorRoundTrip(t *testing.T) {
+	want := facilityCursor{Name: "St. Mary's", ID: "abc-123"}
+
+	encoded, err := encodeCursor(want)
+	if err != nil {
+		t.Fatalf("encodeCursor returned error: %v", err)
+	}
+
+	got, err := decodeFacilityCursor(encoded)
+	if err != nil {
+		t.Fatalf("decodeFacilityCursor returned error: %v", err)
+	}
+	if got != want {
+		t.Errorf("decodeFacilityCursor = %+v, want %+v", got, want)
+	}
+}
+
+func TestScoredFacilityCursorRoundTrip(t *testing.T) {
+	ofs := 87.5
+	want := scoredFacilityCursor{OFSScore: &ofs, Name: "General Hospital", ID: "def-456"}
+
+	encoded, err := encodeCursor(want)
+	if err != nil {
+		t.Fatalf("encodeCursor returned error: %v", err)
+	}
+
+	got, err := decodeScoredFacilityCursor(encoded)
+	if err != nil {
+		t.Fatalf("decodeScoredFacilityCursor returned error: %v", err)
+	}
+	if got.Name != want.Name || got.ID != want.ID || got.OFSScore == nil || *got.OFSScore != *want.OFSScore {
+		t.Errorf("decodeScoredFacilityCursor = %+v, want %+v", got, want)
+	}
+}
+
+func TestScoredFacilityCursorRoundTripNilScore(t *testing.T) {
+	want := scoredFacilityCursor{OFSScore: nil, Name: "Unscored Clinic", ID: "ghi-789"}
+
+	encoded, err := encodeCursor(want)
+	if err != nil {
+		t.Fatalf("encodeCursor returned error: %v", err)
+	}
+
+	got, err := decodeScoredFacilityCursor(encoded)
+	if err != nil {
+		t.Fatalf("decodeScoredFacilityCursor returned error: %v", err)
+	}
+	if got.OFSScore != nil {
+		t.Errorf("decodeScoredFacilityCursor OFSScore = %v, want nil", *got.OFSScore)
+	}
+}
+
+func TestDecodeFacilityCursorInvalid(t *testing.T) {
+	if _, err := decodeFacilityCursor("not-valid-base64!!"); err == nil {
+		t.Error("decodeFacilityCursor: expected error for malformed cursor, got nil")
+	}
+}
@@ -1,23 +1,61 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"golang.org/x/sync/singleflight"
+	"vanurses-api/pkg/domain"
+	"vanurses-api/pkg/utils"
 )
 
+const (
+	regionsCacheTTL  = 1 * time.Hour
+	systemsCacheTTL  = 1 * time.Hour
+	facilityCacheTTL = 10 * time.Minute
+)
+
+// PageParams controls which pagination scheme a list query uses. After takes
+// precedence when set; Offset is honored only while we support the legacy
+// limit/offset callers during the deprecation window.
+type PageParams struct {
+	First  int
+	After  string
+	Offset int
+}
+
 type FacilityRepository struct {
-	db *sqlx.DB
+	db    *sqlx.DB
+	cache Cache
+	sf    singleflight.Group
 }
 
 func NewFacilityRepository(db *sqlx.DB) *FacilityRepository {
-	return &FacilityRepository{db: db}
+	return &FacilityRepository{db: db, cache: NewMemoryCache()}
+}
+
+// SetCache swaps in a different Cache backend (e.g. RedisCache) in place of
+// the in-process default.
+func (r *FacilityRepository) SetCache(cache Cache) {
+	r.cache = cache
+}
+
+// invalidateFacilityCache drops the cached entry for a single facility. Any
+// write path that changes a facility's row or its scores must call this so
+// GetFacilityByID doesn't keep serving a stale cached copy.
+func (r *FacilityRepository) invalidateFacilityCache(ctx context.Context, facilityID string) error {
+	return r.cache.Invalidate(ctx, cacheKey("facility", facilityID))
 }
 
-// GetFacilities returns facilities with optional filtering
-func (r *FacilityRepository) GetFacilities(region, systemName, facilityType string, limit, offset int) ([]map[string]interface{}, int, error) {
-	// Build WHERE clause
+// GetFacilities returns facilities with optional filtering, paginated by
+// keyset cursor on (name, id). Legacy offset pagination is still honored
+// when no cursor is supplied, to give existing callers a deprecation window.
+func (r *FacilityRepository) GetFacilities(region, systemName, facilityType string, page PageParams) ([]utils.Edge, int, utils.PageInfo, error) {
 	var conditions []string
 	var args []interface{}
 	argNum := 1
@@ -38,20 +76,37 @@ func (r *FacilityRepository) GetFacilities(region, systemName, facilityType stri
 		argNum++
 	}
 
-	whereClause := ""
+	// Count total (unaffected by keyset position)
+	countClause := ""
 	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+		countClause = "WHERE " + strings.Join(conditions, " AND ")
 	}
-
-	// Count total
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM facilities f %s", whereClause)
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM facilities f %s", countClause)
 	var total int
-	err := r.db.Get(&total, countQuery, args...)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count facilities: %w", err)
+	if err := r.db.Get(&total, countQuery, args...); err != nil {
+		return nil, 0, utils.PageInfo{}, fmt.Errorf("failed to count facilities: %w", err)
+	}
+
+	hasPreviousPage := false
+	if page.After != "" {
+		cursor, err := decodeFacilityCursor(page.After)
+		if err != nil {
+			return nil, 0, utils.PageInfo{}, err
+		}
+		conditions = append(conditions, fmt.Sprintf("(f.name, f.id::text) > ($%d, $%d)", argNum, argNum+1))
+		args = append(args, cursor.Name, cursor.ID)
+		argNum += 2
+		hasPreviousPage = true
+	} else if page.Offset > 0 {
+		hasPreviousPage = true
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
 	}
 
-	// Fetch facilities
+	// Fetch one extra row so we can tell whether another page follows.
 	query := fmt.Sprintf(`
 		SELECT
 			f.id, f.name, f.system_name, f.city, f.state, f.zip,
@@ -61,33 +116,75 @@ func (r *FacilityRepository) GetFacilities(region, systemName, facilityType stri
 			f.created_at::text, f.updated_at::text
 		FROM facilities f
 		%s
-		ORDER BY f.name
+		ORDER BY f.name, f.id
 		LIMIT $%d OFFSET $%d
 	`, whereClause, argNum, argNum+1)
 
-	args = append(args, limit, offset)
+	// A cursor predicate already positions the query; OFFSET only applies
+	// when we're still on the legacy offset path, per PageParams' doc.
+	queryOffset := page.Offset
+	if page.After != "" {
+		queryOffset = 0
+	}
+	fetchArgs := append(append([]interface{}{}, args...), page.First+1, queryOffset)
 
-	rows, err := r.db.Queryx(query, args...)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to query facilities: %w", err)
+	var facilities []domain.Facility
+	if err := r.db.Select(&facilities, query, fetchArgs...); err != nil {
+		return nil, 0, utils.PageInfo{}, fmt.Errorf("failed to query facilities: %w", err)
 	}
-	defer rows.Close()
 
-	var facilities []map[string]interface{}
-	for rows.Next() {
-		result := make(map[string]interface{})
-		err := rows.MapScan(result)
-		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan facility: %w", err)
+	pageInfo := utils.PageInfo{HasPreviousPage: hasPreviousPage}
+	if len(facilities) > page.First {
+		pageInfo.HasNextPage = true
+		facilities = facilities[:page.First]
+	}
+
+	edges := make([]utils.Edge, len(facilities))
+	for i, f := range facilities {
+		cursor, _ := encodeCursor(facilityCursor{Name: f.Name, ID: f.ID})
+		edges[i] = utils.Edge{Cursor: cursor, Node: f}
+	}
+	if len(edges) > 0 {
+		pageInfo.StartCursor = edges[0].Cursor
+		pageInfo.EndCursor = edges[len(edges)-1].Cursor
+	}
+
+	return edges, total, pageInfo, nil
+}
+
+// GetFacilityByID returns a single facility with all scores, or nil if no
+// facility with that id exists. Hits are cached; the cache is bypassed
+// entirely on a miss so "not found" is never cached.
+func (r *FacilityRepository) GetFacilityByID(ctx context.Context, id string) (*domain.ScoredFacility, error) {
+	key := cacheKey("facility", id)
+	if raw, ok, err := r.cache.Get(ctx, key); err == nil && ok {
+		var cached domain.ScoredFacility
+		if err := json.Unmarshal(raw, &cached); err == nil {
+			return &cached, nil
 		}
-		facilities = append(facilities, result)
 	}
 
-	return facilities, total, nil
+	value, err, _ := r.sf.Do(key, func() (interface{}, error) {
+		facility, err := r.fetchFacilityByID(id)
+		if err != nil || facility == nil {
+			return facility, err
+		}
+		raw, err := json.Marshal(facility)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode cache value for %s: %w", key, err)
+		}
+		if err := r.cache.Set(ctx, key, raw, facilityCacheTTL); err != nil {
+			return nil, err
+		}
+		return facility, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*domain.ScoredFacility), nil
 }
 
-// GetFacilityByID returns a single facility with all scores
-func (r *FacilityRepository) GetFacilityByID(id string) (map[string]interface{}, error) {
+func (r *FacilityRepository) fetchFacilityByID(id string) (*domain.ScoredFacility, error) {
 	query := `
 		SELECT
 			f.id, f.name, f.system_name, f.city, f.state, f.zip,
@@ -103,27 +200,25 @@ func (r *FacilityRepository) GetFacilityByID(id string) (map[string]interface{},
 		WHERE f.id = $1
 	`
 
-	rows, err := r.db.Queryx(query, id)
+	var row facilityScoreRow
+	err := r.db.Get(&row, query, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query facility: %w", err)
-	}
-	defer rows.Close()
-
-	if rows.Next() {
-		result := make(map[string]interface{})
-		err := rows.MapScan(result)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan facility: %w", err)
+		if err == sql.ErrNoRows {
+			return nil, nil
 		}
-		return result, nil
+		return nil, fmt.Errorf("failed to query facility: %w", err)
 	}
 
-	return nil, nil // Not found
+	scored := &domain.ScoredFacility{Facility: row.Facility, FacilityScores: row.FacilityScores}
+	scored.ScoreGrades = scored.FacilityScores.Grades()
+	return scored, nil
 }
 
-// GetFacilitiesWithScores returns facilities with all 9 index scores
-func (r *FacilityRepository) GetFacilitiesWithScores(region string, limit, offset int) ([]map[string]interface{}, int, error) {
-	// Build WHERE clause
+// GetFacilitiesWithScores returns facilities with all 9 index scores,
+// paginated by keyset cursor on (ofs_score, name, id). Legacy offset
+// pagination is still honored when no cursor is supplied, to give existing
+// callers a deprecation window.
+func (r *FacilityRepository) GetFacilitiesWithScores(region string, page PageParams) ([]utils.Edge, int, utils.PageInfo, error) {
 	var conditions []string
 	var args []interface{}
 	argNum := 1
@@ -137,62 +232,115 @@ func (r *FacilityRepository) GetFacilitiesWithScores(region string, limit, offse
 		argNum++
 	}
 
-	whereClause := "WHERE " + strings.Join(conditions, " AND ")
-
-	// Count total
+	// Count total (unaffected by keyset position)
 	countQuery := fmt.Sprintf(`
 		SELECT COUNT(*)
 		FROM facilities f
 		LEFT JOIN facility_scores fs ON f.id = fs.facility_id
-		%s
-	`, whereClause)
+		WHERE %s
+	`, strings.Join(conditions, " AND "))
 	var total int
-	err := r.db.Get(&total, countQuery, args...)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count facilities: %w", err)
+	if err := r.db.Get(&total, countQuery, args...); err != nil {
+		return nil, 0, utils.PageInfo{}, fmt.Errorf("failed to count facilities: %w", err)
+	}
+
+	hasPreviousPage := false
+	if page.After != "" {
+		cursor, err := decodeScoredFacilityCursor(page.After)
+		if err != nil {
+			return nil, 0, utils.PageInfo{}, err
+		}
+		if cursor.OFSScore != nil {
+			// ofs_score sorts DESC but (name, id) tiebreaks ASC, so a plain
+			// row-wise tuple comparison is wrong: it would require name/id to
+			// move in the same direction as ofs_score. Expand it instead; rows
+			// with a NULL ofs_score sort last, so they always follow the cursor.
+			conditions = append(conditions, fmt.Sprintf(
+				"(fs.ofs_score < $%d OR fs.ofs_score IS NULL OR (fs.ofs_score = $%d AND (f.name, f.id::text) > ($%d, $%d)))",
+				argNum, argNum, argNum+1, argNum+2))
+			args = append(args, *cursor.OFSScore, cursor.Name, cursor.ID)
+			argNum += 3
+		} else {
+			conditions = append(conditions, fmt.Sprintf(
+				"fs.ofs_score IS NULL AND (f.name, f.id::text) > ($%d, $%d)", argNum, argNum+1))
+			args = append(args, cursor.Name, cursor.ID)
+			argNum += 2
+		}
+		hasPreviousPage = true
+	} else if page.Offset > 0 {
+		hasPreviousPage = true
 	}
 
-	// Fetch facilities with scores
+	whereClause := "WHERE " + strings.Join(conditions, " AND ")
+
+	// Fetch one extra row so we can tell whether another page follows. Selects
+	// the full facility column set (not just the fields the old UI table
+	// needed) so domain.Facility is never partially populated with spurious
+	// zero values for columns this query simply didn't fetch.
 	query := fmt.Sprintf(`
 		SELECT
 			f.id, f.name, f.system_name, f.city, f.state, f.zip,
 			f.region, f.facility_type, f.bed_count, f.teaching_hospital,
-			f.magnet_status, f.trauma_level,
+			f.magnet_status, f.trauma_level, f.latitude, f.longitude,
+			f.website, f.phone, f.address,
+			f.created_at::text, f.updated_at::text,
 			fs.pci_score, fs.eri_score, fs.pei_score, fs.fsi_score,
 			fs.lssi_score, fs.ali_score, fs.csi_score, fs.qli_score,
 			fs.cci_score, fs.ofs_score
 		FROM facilities f
 		LEFT JOIN facility_scores fs ON f.id = fs.facility_id
 		%s
-		ORDER BY fs.ofs_score DESC NULLS LAST, f.name
+		ORDER BY fs.ofs_score DESC NULLS LAST, f.name, f.id
 		LIMIT $%d OFFSET $%d
 	`, whereClause, argNum, argNum+1)
 
-	args = append(args, limit, offset)
+	// A cursor predicate already positions the query; OFFSET only applies
+	// when we're still on the legacy offset path, per PageParams' doc.
+	queryOffset := page.Offset
+	if page.After != "" {
+		queryOffset = 0
+	}
+	fetchArgs := append(append([]interface{}{}, args...), page.First+1, queryOffset)
 
-	rows, err := r.db.Queryx(query, args...)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to query facilities: %w", err)
+	var rows []facilityScoreRow
+	if err := r.db.Select(&rows, query, fetchArgs...); err != nil {
+		return nil, 0, utils.PageInfo{}, fmt.Errorf("failed to query facilities: %w", err)
 	}
-	defer rows.Close()
 
-	var facilities []map[string]interface{}
-	for rows.Next() {
-		result := make(map[string]interface{})
-		err := rows.MapScan(result)
-		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan facility: %w", err)
-		}
-		// Add letter grades for each score
-		addGrades(result)
-		facilities = append(facilities, result)
+	pageInfo := utils.PageInfo{HasPreviousPage: hasPreviousPage}
+	if len(rows) > page.First {
+		pageInfo.HasNextPage = true
+		rows = rows[:page.First]
 	}
 
-	return facilities, total, nil
+	edges := make([]utils.Edge, len(rows))
+	for i, row := range rows {
+		scored := domain.ScoredFacility{Facility: row.Facility, FacilityScores: row.FacilityScores}
+		scored.ScoreGrades = scored.FacilityScores.Grades()
+		cursor, _ := encodeCursor(scoredFacilityCursor{
+			OFSScore: nullFloat64Ptr(row.OFSScore), Name: row.Name, ID: row.ID,
+		})
+		edges[i] = utils.Edge{Cursor: cursor, Node: scored}
+	}
+	if len(edges) > 0 {
+		pageInfo.StartCursor = edges[0].Cursor
+		pageInfo.EndCursor = edges[len(edges)-1].Cursor
+	}
+
+	return edges, total, pageInfo, nil
+}
+
+// GetRegions returns all distinct regions. The result rarely changes, so
+// it's served from cache.
+func (r *FacilityRepository) GetRegions(ctx context.Context) ([]string, error) {
+	var regions []string
+	err := cachedFetch(ctx, r.cache, &r.sf, cacheKey("regions"), regionsCacheTTL, &regions, func() (interface{}, error) {
+		return r.fetchRegions()
+	})
+	return regions, err
 }
 
-// GetRegions returns all distinct regions
-func (r *FacilityRepository) GetRegions() ([]string, error) {
+func (r *FacilityRepository) fetchRegions() ([]string, error) {
 	query := `
 		SELECT DISTINCT region
 		FROM facilities
@@ -207,8 +355,17 @@ func (r *FacilityRepository) GetRegions() ([]string, error) {
 	return regions, nil
 }
 
-// GetSystems returns all distinct hospital systems
-func (r *FacilityRepository) GetSystems() ([]string, error) {
+// GetSystems returns all distinct hospital systems. The result rarely
+// changes, so it's served from cache.
+func (r *FacilityRepository) GetSystems(ctx context.Context) ([]string, error) {
+	var systems []string
+	err := cachedFetch(ctx, r.cache, &r.sf, cacheKey("systems"), systemsCacheTTL, &systems, func() (interface{}, error) {
+		return r.fetchSystems()
+	})
+	return systems, err
+}
+
+func (r *FacilityRepository) fetchSystems() ([]string, error) {
 	query := `
 		SELECT DISTINCT system_name
 		FROM facilities
@@ -223,65 +380,10 @@ func (r *FacilityRepository) GetSystems() ([]string, error) {
 	return systems, nil
 }
 
-// Helper to convert score to letter grade
-func scoreToGrade(score interface{}) string {
-	if score == nil {
-		return ""
-	}
-
-	var s float64
-	switch v := score.(type) {
-	case float64:
-		s = v
-	case float32:
-		s = float64(v)
-	case int64:
-		s = float64(v)
-	case int:
-		s = float64(v)
-	default:
-		return ""
-	}
-
-	switch {
-	case s >= 90:
-		return "A+"
-	case s >= 85:
-		return "A"
-	case s >= 80:
-		return "A-"
-	case s >= 77:
-		return "B+"
-	case s >= 73:
-		return "B"
-	case s >= 70:
-		return "B-"
-	case s >= 67:
-		return "C+"
-	case s >= 63:
-		return "C"
-	case s >= 60:
-		return "C-"
-	case s >= 57:
-		return "D+"
-	case s >= 53:
-		return "D"
-	case s >= 50:
-		return "D-"
-	default:
-		return "F"
-	}
-}
-
-// Add letter grades to facility result
-func addGrades(result map[string]interface{}) {
-	scoreFields := []string{"pci_score", "eri_score", "pei_score", "fsi_score",
-		"lssi_score", "ali_score", "csi_score", "qli_score", "cci_score", "ofs_score"}
-
-	for _, field := range scoreFields {
-		if score, ok := result[field]; ok {
-			gradeField := strings.Replace(field, "_score", "_grade", 1)
-			result[gradeField] = scoreToGrade(score)
-		}
-	}
+// facilityScoreRow is the sqlx scan target for queries that join facilities
+// with facility_scores; its fields flatten into domain.Facility and
+// domain.FacilityScores.
+type facilityScoreRow struct {
+	domain.Facility
+	domain.FacilityScores
 }
@@ -0,0 +1,286 @@
+package repository
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jmoiron/sqlx"
+	"vanurses-api/internal/service"
+	"vanurses-api/pkg/domain"
+)
+
+// ScoreRepository is the write path for the nine index scores. It holds a
+// pgx pool alongside the usual sqlx handle because bulk ingestion needs
+// pgx's COPY support for throughput; everyday single-row upserts still go
+// through sqlx like the rest of the repository layer.
+type ScoreRepository struct {
+	db         *sqlx.DB
+	pool       *pgxpool.Pool
+	calc       *service.ScoreCalculator
+	facilities *FacilityRepository
+}
+
+func NewScoreRepository(db *sqlx.DB, pool *pgxpool.Pool, calc *service.ScoreCalculator, facilities *FacilityRepository) *ScoreRepository {
+	return &ScoreRepository{db: db, pool: pool, calc: calc, facilities: facilities}
+}
+
+var scoreCSVColumns = []string{
+	"facility_id", "pci_score", "eri_score", "pei_score", "fsi_score",
+	"lssi_score", "ali_score", "csi_score", "qli_score", "cci_score",
+}
+
+// UpsertScores inserts or updates the component scores for a facility and
+// recomputes its OFS composite in the same transaction, so OFS never
+// observably lags a component-score write.
+func (r *ScoreRepository) UpsertScores(ctx context.Context, facilityID string, scores domain.FacilityScores) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin upsert transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO facility_scores (
+			facility_id, pci_score, eri_score, pei_score, fsi_score,
+			lssi_score, ali_score, csi_score, qli_score, cci_score, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, now())
+		ON CONFLICT (facility_id) DO UPDATE SET
+			pci_score = EXCLUDED.pci_score,
+			eri_score = EXCLUDED.eri_score,
+			pei_score = EXCLUDED.pei_score,
+			fsi_score = EXCLUDED.fsi_score,
+			lssi_score = EXCLUDED.lssi_score,
+			ali_score = EXCLUDED.ali_score,
+			csi_score = EXCLUDED.csi_score,
+			qli_score = EXCLUDED.qli_score,
+			cci_score = EXCLUDED.cci_score,
+			updated_at = EXCLUDED.updated_at
+	`, facilityID, scores.PCIScore, scores.ERIScore, scores.PEIScore, scores.FSIScore,
+		scores.LSSIScore, scores.ALIScore, scores.CSIScore, scores.QLIScore, scores.CCIScore)
+	if err != nil {
+		return fmt.Errorf("failed to upsert scores for facility %s: %w", facilityID, err)
+	}
+
+	if err := r.calc.RecomputeFacility(ctx, tx, facilityID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return r.facilities.invalidateFacilityCache(ctx, facilityID)
+}
+
+// BulkUpsertScoresFromCSV loads component scores from a CSV reader (header
+// row: facility_id,pci_score,eri_score,pei_score,fsi_score,lssi_score,
+// ali_score,csi_score,qli_score,cci_score) via a pgx COPY into a staging
+// table, upserts them into facility_scores, and recomputes OFS for every
+// affected facility. It returns the number of rows ingested.
+func (r *ScoreRepository) BulkUpsertScoresFromCSV(ctx context.Context, reader io.Reader) (int64, error) {
+	rows, err := parseScoreCSV(reader)
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin bulk upsert transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		CREATE TEMP TABLE facility_scores_staging (
+			facility_id uuid, pci_score numeric, eri_score numeric, pei_score numeric,
+			fsi_score numeric, lssi_score numeric, ali_score numeric, csi_score numeric,
+			qli_score numeric, cci_score numeric
+		) ON COMMIT DROP
+	`); err != nil {
+		return 0, fmt.Errorf("failed to create staging table: %w", err)
+	}
+
+	copied, err := tx.CopyFrom(ctx,
+		pgx.Identifier{"facility_scores_staging"},
+		scoreCSVColumns,
+		pgx.CopyFromSlice(len(rows), func(i int) ([]interface{}, error) {
+			row := rows[i]
+			return []interface{}{
+				row.FacilityID, row.PCIScore, row.ERIScore, row.PEIScore, row.FSIScore,
+				row.LSSIScore, row.ALIScore, row.CSIScore, row.QLIScore, row.CCIScore,
+			}, nil
+		}),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to copy scores into staging: %w", err)
+	}
+
+	// A CSV legitimately containing two rows for the same facility_id (e.g. a
+	// correction appended to the same file) would otherwise make Postgres
+	// raise "ON CONFLICT DO UPDATE command cannot affect row a second time"
+	// and fail the whole batch. Keep only the last row per facility_id -
+	// ctid DESC reflects insertion order within this single COPY.
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO facility_scores (
+			facility_id, pci_score, eri_score, pei_score, fsi_score,
+			lssi_score, ali_score, csi_score, qli_score, cci_score, updated_at
+		)
+		SELECT facility_id, pci_score, eri_score, pei_score, fsi_score,
+			lssi_score, ali_score, csi_score, qli_score, cci_score, now()
+		FROM (
+			SELECT DISTINCT ON (facility_id) facility_id, pci_score, eri_score,
+				pei_score, fsi_score, lssi_score, ali_score, csi_score,
+				qli_score, cci_score
+			FROM facility_scores_staging
+			ORDER BY facility_id, ctid DESC
+		) deduped
+		ON CONFLICT (facility_id) DO UPDATE SET
+			pci_score = EXCLUDED.pci_score,
+			eri_score = EXCLUDED.eri_score,
+			pei_score = EXCLUDED.pei_score,
+			fsi_score = EXCLUDED.fsi_score,
+			lssi_score = EXCLUDED.lssi_score,
+			ali_score = EXCLUDED.ali_score,
+			csi_score = EXCLUDED.csi_score,
+			qli_score = EXCLUDED.qli_score,
+			cci_score = EXCLUDED.cci_score,
+			updated_at = EXCLUDED.updated_at
+	`); err != nil {
+		return 0, fmt.Errorf("failed to upsert scores from staging: %w", err)
+	}
+
+	var facilityIDs []string
+	rowsIter, err := tx.Query(ctx, `SELECT DISTINCT facility_id::text FROM facility_scores_staging`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list facilities to recompute: %w", err)
+	}
+	for rowsIter.Next() {
+		var id string
+		if err := rowsIter.Scan(&id); err != nil {
+			rowsIter.Close()
+			return 0, fmt.Errorf("failed to scan staged facility id: %w", err)
+		}
+		facilityIDs = append(facilityIDs, id)
+	}
+	rowsIter.Close()
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit bulk upsert: %w", err)
+	}
+
+	for _, id := range facilityIDs {
+		recomputeTx, err := r.db.BeginTxx(ctx, nil)
+		if err != nil {
+			return copied, fmt.Errorf("failed to begin recompute transaction: %w", err)
+		}
+		if err := r.calc.RecomputeFacility(ctx, recomputeTx, id); err != nil {
+			recomputeTx.Rollback()
+			return copied, err
+		}
+		if err := recomputeTx.Commit(); err != nil {
+			return copied, fmt.Errorf("failed to commit recompute transaction: %w", err)
+		}
+		if err := r.facilities.invalidateFacilityCache(ctx, id); err != nil {
+			return copied, err
+		}
+	}
+
+	return copied, nil
+}
+
+type scoreCSVRow struct {
+	FacilityID string
+	PCIScore   *float64
+	ERIScore   *float64
+	PEIScore   *float64
+	FSIScore   *float64
+	LSSIScore  *float64
+	ALIScore   *float64
+	CSIScore   *float64
+	QLIScore   *float64
+	CCIScore   *float64
+}
+
+func parseScoreCSV(reader io.Reader) ([]scoreCSVRow, error) {
+	csvReader := csv.NewReader(reader)
+	header, err := csvReader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columnIndex := make(map[string]int, len(header))
+	for i, name := range header {
+		columnIndex[strings.TrimSpace(strings.ToLower(name))] = i
+	}
+	if _, ok := columnIndex["facility_id"]; !ok {
+		return nil, fmt.Errorf("CSV is missing required facility_id column")
+	}
+
+	var rows []scoreCSVRow
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV row: %w", err)
+		}
+
+		row := scoreCSVRow{FacilityID: record[columnIndex["facility_id"]]}
+		for _, col := range scoreCSVColumns[1:] {
+			idx, ok := columnIndex[col]
+			if !ok {
+				continue
+			}
+			raw := strings.TrimSpace(record[idx])
+			if raw == "" {
+				continue
+			}
+			value, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %q for column %s: %w", raw, col, err)
+			}
+			switch col {
+			case "pci_score":
+				row.PCIScore = &value
+			case "eri_score":
+				row.ERIScore = &value
+			case "pei_score":
+				row.PEIScore = &value
+			case "fsi_score":
+				row.FSIScore = &value
+			case "lssi_score":
+				row.LSSIScore = &value
+			case "ali_score":
+				row.ALIScore = &value
+			case "csi_score":
+				row.CSIScore = &value
+			case "qli_score":
+				row.QLIScore = &value
+			case "cci_score":
+				row.CCIScore = &value
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
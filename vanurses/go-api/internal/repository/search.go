@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"vanurses-api/pkg/domain"
+	"vanurses-api/pkg/utils"
+)
+
+// kmPerLatDegree approximates the length of one degree of latitude in
+// kilometers, used to turn a radius search into a lat/lng bounding box.
+const kmPerLatDegree = 111.32
+
+// boundingBox returns the lat/lng bounding box that contains every point
+// within radiusKm of (lat, lng). It's a cheap over-approximation (a circle's
+// bounding square), meant to be combined with the exact earth_distance_km
+// filter: the box lets the planner use idx_facilities_lat_lng as a range
+// scan before the haversine function runs per-row.
+//
+// ok is false when the box would cross the ±180° antimeridian (e.g. a
+// radius search near the Aleutian Islands). A plain min/max longitude range
+// can't represent a wrapped box, and a naive one would wrongly exclude real
+// facilities just across the dateline - so the caller should skip the
+// prefilter and fall back to the plain earth_distance_km filter instead.
+func boundingBox(lat, lng, radiusKm float64) (latMin, latMax, lngMin, lngMax float64, ok bool) {
+	latDelta := radiusKm / kmPerLatDegree
+	cosLat := math.Cos(lat * math.Pi / 180)
+	if math.Abs(cosLat) < 0.01 {
+		// Near a pole the circle's longitude span grows without bound (and
+		// dividing by cosLat would blow up), so the box spans every longitude.
+		return lat - latDelta, lat + latDelta, -180, 180, true
+	}
+	lngDelta := radiusKm / (kmPerLatDegree * math.Abs(cosLat))
+	lngMin, lngMax = lng-lngDelta, lng+lngDelta
+	if lngMin < -180 || lngMax > 180 {
+		return 0, 0, 0, 0, false
+	}
+	return lat - latDelta, lat + latDelta, lngMin, lngMax, true
+}
+
+// SearchParams bundles the filters accepted by SearchFacilities. Query drives
+// full-text ranking; Lat/Lng/RadiusKm scope results to a geographic radius.
+// Regions, Types and MinOFS narrow the result set the same way the equality
+// filters on GetFacilities do.
+type SearchParams struct {
+	Query    string
+	Lat      *float64
+	Lng      *float64
+	RadiusKm *float64
+	Regions  []string
+	Types    []string
+	MinOFS   *float64
+	Page     PageParams
+}
+
+// searchCursor is the keyset position for search result ordering: text rank
+// first (nil when the search has no text query, in which case results are
+// ordered by name alone), then (name, id) to break ties deterministically.
+type searchCursor struct {
+	Rank *float64 `json:"r"`
+	Name string   `json:"n"`
+	ID   string   `json:"i"`
+}
+
+// SearchFacilities performs full-text ranked search over name/system_name/
+// city/address, optionally narrowed to a geographic radius and/or region,
+// type and score filters. Results carry the same shape (including letter
+// grades) as GetFacilitiesWithScores.
+func (r *FacilityRepository) SearchFacilities(params SearchParams) ([]utils.Edge, int, utils.PageInfo, error) {
+	var conditions []string
+	var args []interface{}
+	argNum := 1
+
+	rankExpr := "NULL::real"
+	orderExpr := "f.name"
+	if params.Query != "" {
+		conditions = append(conditions, fmt.Sprintf("f.search_vector @@ plainto_tsquery('english', $%d)", argNum))
+		rankExpr = fmt.Sprintf("ts_rank_cd(f.search_vector, plainto_tsquery('english', $%d))", argNum)
+		orderExpr = "rank DESC"
+		args = append(args, params.Query)
+		argNum++
+	}
+
+	if params.Lat != nil && params.Lng != nil && params.RadiusKm != nil {
+		latMin, latMax, lngMin, lngMax, ok := boundingBox(*params.Lat, *params.Lng, *params.RadiusKm)
+		if ok {
+			// The bounding box is a sargable pre-filter idx_facilities_lat_lng
+			// can satisfy with a range scan; earth_distance_km still runs, but
+			// only over the rows the box already narrowed down.
+			conditions = append(conditions, fmt.Sprintf(
+				"f.latitude BETWEEN $%d AND $%d AND f.longitude BETWEEN $%d AND $%d AND earth_distance_km(f.latitude, f.longitude, $%d, $%d) <= $%d",
+				argNum, argNum+1, argNum+2, argNum+3, argNum+4, argNum+5, argNum+6))
+			args = append(args, latMin, latMax, lngMin, lngMax, *params.Lat, *params.Lng, *params.RadiusKm)
+			argNum += 7
+		} else {
+			// The box would cross the antimeridian; fall back to the exact
+			// filter alone rather than silently dropping valid results.
+			conditions = append(conditions, fmt.Sprintf(
+				"earth_distance_km(f.latitude, f.longitude, $%d, $%d) <= $%d",
+				argNum, argNum+1, argNum+2))
+			args = append(args, *params.Lat, *params.Lng, *params.RadiusKm)
+			argNum += 3
+		}
+	}
+
+	if len(params.Regions) > 0 {
+		conditions = append(conditions, fmt.Sprintf("f.region = ANY($%d)", argNum))
+		args = append(args, stringsToPQArray(params.Regions))
+		argNum++
+	}
+	if len(params.Types) > 0 {
+		conditions = append(conditions, fmt.Sprintf("f.facility_type = ANY($%d)", argNum))
+		args = append(args, stringsToPQArray(params.Types))
+		argNum++
+	}
+	if params.MinOFS != nil {
+		conditions = append(conditions, fmt.Sprintf("fs.ofs_score >= $%d", argNum))
+		args = append(args, *params.MinOFS)
+		argNum++
+	}
+
+	countClause := ""
+	if len(conditions) > 0 {
+		countClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	countQuery := fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM facilities f
+		LEFT JOIN facility_scores fs ON f.id = fs.facility_id
+		%s
+	`, countClause)
+	var total int
+	if err := r.db.Get(&total, countQuery, args...); err != nil {
+		return nil, 0, utils.PageInfo{}, fmt.Errorf("failed to count search results: %w", err)
+	}
+
+	hasPreviousPage := false
+	if params.Page.After != "" {
+		cursor, err := decodeSearchCursor(params.Page.After)
+		if err != nil {
+			return nil, 0, utils.PageInfo{}, err
+		}
+		if cursor.Rank != nil {
+			// rank sorts DESC but (name, id) tiebreaks ASC - same expansion as
+			// the ofs_score cursor in facility_repo.go, for the same reason: a
+			// plain row-wise tuple comparison would require the tiebreak to
+			// move in the same direction as rank, which is wrong.
+			conditions = append(conditions, fmt.Sprintf(
+				"(%s < $%d OR (%s = $%d AND (f.name, f.id::text) > ($%d, $%d)))",
+				rankExpr, argNum, rankExpr, argNum, argNum+1, argNum+2))
+			args = append(args, *cursor.Rank, cursor.Name, cursor.ID)
+			argNum += 3
+		} else {
+			conditions = append(conditions, fmt.Sprintf("(f.name, f.id::text) > ($%d, $%d)", argNum, argNum+1))
+			args = append(args, cursor.Name, cursor.ID)
+			argNum += 2
+		}
+		hasPreviousPage = true
+	} else if params.Page.Offset > 0 {
+		hasPreviousPage = true
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			f.id, f.name, f.system_name, f.city, f.state, f.zip,
+			f.region, f.facility_type, f.bed_count, f.teaching_hospital,
+			f.magnet_status, f.trauma_level, f.latitude, f.longitude,
+			f.website, f.phone, f.address,
+			f.created_at::text, f.updated_at::text,
+			fs.pci_score, fs.eri_score, fs.pei_score, fs.fsi_score,
+			fs.lssi_score, fs.ali_score, fs.csi_score, fs.qli_score,
+			fs.cci_score, fs.ofs_score,
+			%s AS rank
+		FROM facilities f
+		LEFT JOIN facility_scores fs ON f.id = fs.facility_id
+		%s
+		ORDER BY %s, f.name, f.id
+		LIMIT $%d OFFSET $%d
+	`, rankExpr, whereClause, orderExpr, argNum, argNum+1)
+
+	// A cursor predicate already positions the query; OFFSET only applies
+	// when we're still on the legacy offset path, per PageParams' doc.
+	queryOffset := params.Page.Offset
+	if params.Page.After != "" {
+		queryOffset = 0
+	}
+	fetchArgs := append(append([]interface{}{}, args...), params.Page.First+1, queryOffset)
+
+	var rows []searchRow
+	if err := r.db.Select(&rows, query, fetchArgs...); err != nil {
+		return nil, 0, utils.PageInfo{}, fmt.Errorf("failed to query search results: %w", err)
+	}
+
+	pageInfo := utils.PageInfo{HasPreviousPage: hasPreviousPage}
+	if len(rows) > params.Page.First {
+		pageInfo.HasNextPage = true
+		rows = rows[:params.Page.First]
+	}
+
+	edges := make([]utils.Edge, len(rows))
+	for i, row := range rows {
+		scored := domain.ScoredFacility{Facility: row.Facility, FacilityScores: row.FacilityScores}
+		scored.ScoreGrades = scored.FacilityScores.Grades()
+		cursor, _ := encodeCursor(searchCursor{Rank: nullFloat64Ptr(row.Rank), Name: row.Name, ID: row.ID})
+		edges[i] = utils.Edge{Cursor: cursor, Node: scored}
+	}
+	if len(edges) > 0 {
+		pageInfo.StartCursor = edges[0].Cursor
+		pageInfo.EndCursor = edges[len(edges)-1].Cursor
+	}
+
+	return edges, total, pageInfo, nil
+}
+
+// searchRow is the sqlx scan target for SearchFacilities; Rank holds the
+// ts_rank_cd value (NULL when the search had no text query).
+type searchRow struct {
+	domain.Facility
+	domain.FacilityScores
+	Rank domain.NullFloat64 `db:"rank"`
+}
+
+// stringsToPQArray formats a Go string slice as a Postgres array literal
+// suitable for binding to an ANY($n) predicate. Backslashes must be escaped
+// before quotes, since quote-escaping a value containing a literal backslash
+// would otherwise produce a malformed (or misparsed) array literal.
+func stringsToPQArray(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		escaped := strings.ReplaceAll(v, `\`, `\\`)
+		escaped = strings.ReplaceAll(escaped, `"`, `\"`)
+		quoted[i] = `"` + escaped + `"`
+	}
+	return "{" + strings.Join(quoted, ",") + "}"
+}
@@ -0,0 +1,41 @@
+package repository
+
+import "testing"
+
+func TestStringsToPQArrayEscapesBackslashAndQuote(t *testing.T) {
+	got := stringsToPQArray([]string{`NW`, `weird\"value`})
+	want := `{"NW","weird\\\"value"}`
+	if got != want {
+		t.Errorf("stringsToPQArray = %q, want %q", got, want)
+	}
+}
+
+func TestBoundingBoxContainsOrigin(t *testing.T) {
+	latMin, latMax, lngMin, lngMax, ok := boundingBox(40.0, -75.0, 10.0)
+	if !ok {
+		t.Fatal("boundingBox: expected ok=true for an ordinary point")
+	}
+	if !(latMin < 40.0 && 40.0 < latMax) {
+		t.Errorf("boundingBox latitude range [%f, %f] does not contain 40.0", latMin, latMax)
+	}
+	if !(lngMin < -75.0 && -75.0 < lngMax) {
+		t.Errorf("boundingBox longitude range [%f, %f] does not contain -75.0", lngMin, lngMax)
+	}
+}
+
+func TestBoundingBoxNearPoleDoesNotDivideByZero(t *testing.T) {
+	latMin, latMax, lngMin, lngMax, ok := boundingBox(89.999, 0, 5.0)
+	if !ok {
+		t.Fatal("boundingBox: expected ok=true near the pole")
+	}
+	if latMin >= latMax || lngMin >= lngMax {
+		t.Errorf("boundingBox near pole produced degenerate range: lat [%f,%f] lng [%f,%f]", latMin, latMax, lngMin, lngMax)
+	}
+}
+
+func TestBoundingBoxNearAntimeridianSkipsPrefilter(t *testing.T) {
+	_, _, _, _, ok := boundingBox(51.88, 179.5, 50)
+	if ok {
+		t.Error("boundingBox: expected ok=false when the box crosses the antimeridian")
+	}
+}
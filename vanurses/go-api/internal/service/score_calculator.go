@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"vanurses-api/pkg/domain"
+)
+
+// ScoreWeights is the active weight set used to combine the nine component
+// indices into the OFS composite. Version identifies the score_weights row
+// it came from, so a historical OFS value can always be traced back to the
+// weights that produced it.
+type ScoreWeights struct {
+	Version    int     `db:"version"`
+	PCIWeight  float64 `db:"pci_weight"`
+	ERIWeight  float64 `db:"eri_weight"`
+	PEIWeight  float64 `db:"pei_weight"`
+	FSIWeight  float64 `db:"fsi_weight"`
+	LSSIWeight float64 `db:"lssi_weight"`
+	ALIWeight  float64 `db:"ali_weight"`
+	CSIWeight  float64 `db:"csi_weight"`
+	QLIWeight  float64 `db:"qli_weight"`
+	CCIWeight  float64 `db:"cci_weight"`
+}
+
+// ScoreCalculator recomputes a facility's OFS composite score as a weighted
+// mean of its nine component indices.
+type ScoreCalculator struct {
+	db *sqlx.DB
+}
+
+func NewScoreCalculator(db *sqlx.DB) *ScoreCalculator {
+	return &ScoreCalculator{db: db}
+}
+
+// ActiveWeights returns the currently active weight set.
+func (c *ScoreCalculator) ActiveWeights(ctx context.Context) (ScoreWeights, error) {
+	return activeWeights(ctx, c.db)
+}
+
+type queryer interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+func activeWeights(ctx context.Context, q queryer) (ScoreWeights, error) {
+	var w ScoreWeights
+	err := q.GetContext(ctx, &w, `
+		SELECT version, pci_weight, eri_weight, pei_weight, fsi_weight,
+			lssi_weight, ali_weight, csi_weight, qli_weight, cci_weight
+		FROM score_weights
+		WHERE active = true
+	`)
+	if err != nil {
+		return ScoreWeights{}, fmt.Errorf("failed to load active score weights: %w", err)
+	}
+	return w, nil
+}
+
+// ComputeOFS returns the weighted mean of the present (non-NULL) component
+// scores in s, re-normalized over the weights of the components that are
+// actually present. Returns an invalid NullFloat64 if no component score is
+// set yet.
+func (c *ScoreCalculator) ComputeOFS(scores domain.FacilityScores, weights ScoreWeights) domain.NullFloat64 {
+	type component struct {
+		value, weight float64
+	}
+
+	components := []component{}
+	add := func(score domain.NullFloat64, weight float64) {
+		if score.Valid {
+			components = append(components, component{score.Float64, weight})
+		}
+	}
+	add(scores.PCIScore, weights.PCIWeight)
+	add(scores.ERIScore, weights.ERIWeight)
+	add(scores.PEIScore, weights.PEIWeight)
+	add(scores.FSIScore, weights.FSIWeight)
+	add(scores.LSSIScore, weights.LSSIWeight)
+	add(scores.ALIScore, weights.ALIWeight)
+	add(scores.CSIScore, weights.CSIWeight)
+	add(scores.QLIScore, weights.QLIWeight)
+	add(scores.CCIScore, weights.CCIWeight)
+
+	var weightedSum, totalWeight float64
+	for _, comp := range components {
+		weightedSum += comp.value * comp.weight
+		totalWeight += comp.weight
+	}
+	if totalWeight == 0 {
+		return domain.NullFloat64{}
+	}
+
+	return domain.NullFloat64{NullFloat64: sql.NullFloat64{Float64: weightedSum / totalWeight, Valid: true}}
+}
+
+// RecomputeFacility recomputes and persists the OFS score for a single
+// facility using tx, so callers can fold it into a larger upsert
+// transaction.
+func (c *ScoreCalculator) RecomputeFacility(ctx context.Context, tx *sqlx.Tx, facilityID string) error {
+	weights, err := activeWeights(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	var scores domain.FacilityScores
+	err = tx.GetContext(ctx, &scores, `
+		SELECT pci_score, eri_score, pei_score, fsi_score, lssi_score,
+			ali_score, csi_score, qli_score, cci_score
+		FROM facility_scores
+		WHERE facility_id = $1
+	`, facilityID)
+	if err != nil {
+		return fmt.Errorf("failed to load scores for facility %s: %w", facilityID, err)
+	}
+
+	ofs := c.ComputeOFS(scores, weights)
+	_, err = tx.ExecContext(ctx, `
+		UPDATE facility_scores
+		SET ofs_score = $1, ofs_weight_version = $2, updated_at = now()
+		WHERE facility_id = $3
+	`, ofs, weights.Version, facilityID)
+	if err != nil {
+		return fmt.Errorf("failed to persist recomputed ofs for facility %s: %w", facilityID, err)
+	}
+
+	return nil
+}
+
+// RecomputeRegion recomputes the OFS score for every scored facility in
+// region (or every scored facility, when region is empty), one transaction
+// per facility. It returns the number of facilities recomputed.
+func (c *ScoreCalculator) RecomputeRegion(ctx context.Context, region string) (int, error) {
+	query := `SELECT f.id FROM facilities f JOIN facility_scores fs ON f.id = fs.facility_id`
+	args := []interface{}{}
+	if region != "" {
+		query += " WHERE f.region = $1"
+		args = append(args, region)
+	}
+
+	var facilityIDs []string
+	if err := c.db.SelectContext(ctx, &facilityIDs, query, args...); err != nil {
+		return 0, fmt.Errorf("failed to list facilities for recompute: %w", err)
+	}
+
+	for _, id := range facilityIDs {
+		tx, err := c.db.BeginTxx(ctx, nil)
+		if err != nil {
+			return 0, fmt.Errorf("failed to begin recompute transaction: %w", err)
+		}
+		if err := c.RecomputeFacility(ctx, tx, id); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+		if err := tx.Commit(); err != nil {
+			return 0, fmt.Errorf("failed to commit recompute transaction: %w", err)
+		}
+	}
+
+	return len(facilityIDs), nil
+}
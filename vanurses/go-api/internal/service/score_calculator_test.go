@@ -0,0 +1,57 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+
+	"vanurses-api/pkg/domain"
+)
+
+func nf(v float64) domain.NullFloat64 {
+	return domain.NullFloat64{NullFloat64: sql.NullFloat64{Float64: v, Valid: true}}
+}
+
+func TestComputeOFSWeightedMean(t *testing.T) {
+	calc := &ScoreCalculator{}
+	weights := ScoreWeights{
+		PCIWeight: 1, ERIWeight: 1, PEIWeight: 1, FSIWeight: 1,
+		LSSIWeight: 1, ALIWeight: 1, CSIWeight: 1, QLIWeight: 1, CCIWeight: 1,
+	}
+	scores := domain.FacilityScores{
+		PCIScore: nf(90), ERIScore: nf(80), PEIScore: nf(70), FSIScore: nf(60),
+		LSSIScore: nf(90), ALIScore: nf(80), CSIScore: nf(70), QLIScore: nf(60), CCIScore: nf(50),
+	}
+
+	ofs := calc.ComputeOFS(scores, weights)
+	if !ofs.Valid {
+		t.Fatal("ComputeOFS: expected a valid result when every component is present")
+	}
+	want := (90.0 + 80 + 70 + 60 + 90 + 80 + 70 + 60 + 50) / 9
+	if ofs.Float64 != want {
+		t.Errorf("ComputeOFS = %v, want %v", ofs.Float64, want)
+	}
+}
+
+func TestComputeOFSRenormalizesOverPresentComponents(t *testing.T) {
+	calc := &ScoreCalculator{}
+	weights := ScoreWeights{PCIWeight: 2, ERIWeight: 1}
+	scores := domain.FacilityScores{PCIScore: nf(90)}
+
+	ofs := calc.ComputeOFS(scores, weights)
+	if !ofs.Valid {
+		t.Fatal("ComputeOFS: expected a valid result when one component is present")
+	}
+	if ofs.Float64 != 90 {
+		t.Errorf("ComputeOFS with only PCI present = %v, want 90 (renormalized, not diluted by ERI's missing weight)", ofs.Float64)
+	}
+}
+
+func TestComputeOFSNoComponentsPresent(t *testing.T) {
+	calc := &ScoreCalculator{}
+	weights := ScoreWeights{PCIWeight: 1, ERIWeight: 1}
+
+	ofs := calc.ComputeOFS(domain.FacilityScores{}, weights)
+	if ofs.Valid {
+		t.Errorf("ComputeOFS with no components present = %v, want invalid", ofs.Float64)
+	}
+}
@@ -0,0 +1,38 @@
+package domain
+
+// Facility is the typed representation of a row in the facilities table.
+// JSON tags intentionally mirror the db tags (snake_case): this is a direct
+// replacement for the old MapScan-based handlers, which emitted raw SQL
+// column names as top-level keys, and callers already depend on that shape.
+type Facility struct {
+	ID               string      `db:"id" json:"id"`
+	Name             string      `db:"name" json:"name"`
+	SystemName       NullString  `db:"system_name" json:"system_name"`
+	City             string      `db:"city" json:"city"`
+	State            string      `db:"state" json:"state"`
+	Zip              string      `db:"zip" json:"zip"`
+	Region           NullString  `db:"region" json:"region"`
+	FacilityType     NullString  `db:"facility_type" json:"facility_type"`
+	BedCount         NullInt64   `db:"bed_count" json:"bed_count"`
+	TeachingHospital bool        `db:"teaching_hospital" json:"teaching_hospital"`
+	MagnetStatus     bool        `db:"magnet_status" json:"magnet_status"`
+	TraumaLevel      NullString  `db:"trauma_level" json:"trauma_level"`
+	Latitude         NullFloat64 `db:"latitude" json:"latitude"`
+	Longitude        NullFloat64 `db:"longitude" json:"longitude"`
+	Website          NullString  `db:"website" json:"website"`
+	Phone            NullString  `db:"phone" json:"phone"`
+	Address          NullString  `db:"address" json:"address"`
+	CreatedAt        string      `db:"created_at" json:"created_at"`
+	UpdatedAt        string      `db:"updated_at" json:"updated_at"`
+}
+
+// ScoredFacility pairs a Facility with its index scores and derived letter
+// grades, the shape returned by endpoints that join facility_scores. Scores
+// and grades are embedded (not nested) so the JSON output stays a single
+// flat object matching the pre-refactor MapScan shape: *_score fields
+// alongside their *_grade siblings.
+type ScoredFacility struct {
+	Facility
+	FacilityScores
+	ScoreGrades
+}
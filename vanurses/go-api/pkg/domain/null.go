@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"database/sql"
+	"encoding/json"
+)
+
+// NullString wraps sql.NullString so facility columns that may be NULL
+// still marshal to plain `null` instead of the `{String,Valid}` shape.
+type NullString struct {
+	sql.NullString
+}
+
+func (n NullString) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.String)
+}
+
+// NullFloat64 wraps sql.NullFloat64 for the same reason as NullString.
+type NullFloat64 struct {
+	sql.NullFloat64
+}
+
+func (n NullFloat64) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Float64)
+}
+
+// NullInt64 wraps sql.NullInt64 for the same reason as NullString.
+type NullInt64 struct {
+	sql.NullInt64
+}
+
+func (n NullInt64) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Int64)
+}
@@ -0,0 +1,107 @@
+package domain
+
+// FacilityScores holds the nine index scores plus the composite OFS score
+// for a facility. Any of them may be NULL until a facility has been scored.
+type FacilityScores struct {
+	PCIScore  NullFloat64 `db:"pci_score" json:"pci_score"`
+	ERIScore  NullFloat64 `db:"eri_score" json:"eri_score"`
+	PEIScore  NullFloat64 `db:"pei_score" json:"pei_score"`
+	FSIScore  NullFloat64 `db:"fsi_score" json:"fsi_score"`
+	LSSIScore NullFloat64 `db:"lssi_score" json:"lssi_score"`
+	ALIScore  NullFloat64 `db:"ali_score" json:"ali_score"`
+	CSIScore  NullFloat64 `db:"csi_score" json:"csi_score"`
+	QLIScore  NullFloat64 `db:"qli_score" json:"qli_score"`
+	CCIScore  NullFloat64 `db:"cci_score" json:"cci_score"`
+	OFSScore  NullFloat64 `db:"ofs_score" json:"ofs_score"`
+}
+
+// Grade is a letter grade derived from a 0-100 index score.
+type Grade string
+
+const (
+	GradeAPlus Grade = "A+"
+	GradeA     Grade = "A"
+	GradeAMin  Grade = "A-"
+	GradeBPlus Grade = "B+"
+	GradeB     Grade = "B"
+	GradeBMin  Grade = "B-"
+	GradeCPlus Grade = "C+"
+	GradeC     Grade = "C"
+	GradeCMin  Grade = "C-"
+	GradeDPlus Grade = "D+"
+	GradeD     Grade = "D"
+	GradeDMin  Grade = "D-"
+	GradeF     Grade = "F"
+	GradeNone  Grade = ""
+)
+
+// ScoreGrades mirrors FacilityScores with each score converted to a Grade.
+// JSON tags are `*_grade` so that, embedded in ScoredFacility, each grade
+// sits as a flat sibling of its `*_score` field, matching the old
+// addGrades-on-a-map output.
+type ScoreGrades struct {
+	PCI  Grade `json:"pci_grade"`
+	ERI  Grade `json:"eri_grade"`
+	PEI  Grade `json:"pei_grade"`
+	FSI  Grade `json:"fsi_grade"`
+	LSSI Grade `json:"lssi_grade"`
+	ALI  Grade `json:"ali_grade"`
+	CSI  Grade `json:"csi_grade"`
+	QLI  Grade `json:"qli_grade"`
+	CCI  Grade `json:"cci_grade"`
+	OFS  Grade `json:"ofs_grade"`
+}
+
+// Grades converts every score on s to its letter grade.
+func (s FacilityScores) Grades() ScoreGrades {
+	return ScoreGrades{
+		PCI:  gradeOf(s.PCIScore),
+		ERI:  gradeOf(s.ERIScore),
+		PEI:  gradeOf(s.PEIScore),
+		FSI:  gradeOf(s.FSIScore),
+		LSSI: gradeOf(s.LSSIScore),
+		ALI:  gradeOf(s.ALIScore),
+		CSI:  gradeOf(s.CSIScore),
+		QLI:  gradeOf(s.QLIScore),
+		CCI:  gradeOf(s.CCIScore),
+		OFS:  gradeOf(s.OFSScore),
+	}
+}
+
+func gradeOf(score NullFloat64) Grade {
+	if !score.Valid {
+		return GradeNone
+	}
+	return scoreToGrade(score.Float64)
+}
+
+func scoreToGrade(s float64) Grade {
+	switch {
+	case s >= 90:
+		return GradeAPlus
+	case s >= 85:
+		return GradeA
+	case s >= 80:
+		return GradeAMin
+	case s >= 77:
+		return GradeBPlus
+	case s >= 73:
+		return GradeB
+	case s >= 70:
+		return GradeBMin
+	case s >= 67:
+		return GradeCPlus
+	case s >= 63:
+		return GradeC
+	case s >= 60:
+		return GradeCMin
+	case s >= 57:
+		return GradeDPlus
+	case s >= 53:
+		return GradeD
+	case s >= 50:
+		return GradeDMin
+	default:
+		return GradeF
+	}
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+)
+
+func TestScoreToGradeBoundaries(t *testing.T) {
+	cases := []struct {
+		score float64
+		want  Grade
+	}{
+		{100, GradeAPlus},
+		{90, GradeAPlus},
+		{89.9, GradeA},
+		{85, GradeA},
+		{84.9, GradeAMin},
+		{80, GradeAMin},
+		{79.9, GradeBPlus},
+		{77, GradeBPlus},
+		{76.9, GradeB},
+		{73, GradeB},
+		{72.9, GradeBMin},
+		{70, GradeBMin},
+		{69.9, GradeCPlus},
+		{67, GradeCPlus},
+		{66.9, GradeC},
+		{63, GradeC},
+		{62.9, GradeCMin},
+		{60, GradeCMin},
+		{59.9, GradeDPlus},
+		{57, GradeDPlus},
+		{56.9, GradeD},
+		{53, GradeD},
+		{52.9, GradeDMin},
+		{50, GradeDMin},
+		{49.9, GradeF},
+		{0, GradeF},
+	}
+
+	for _, tc := range cases {
+		if got := scoreToGrade(tc.score); got != tc.want {
+			t.Errorf("scoreToGrade(%v) = %q, want %q", tc.score, got, tc.want)
+		}
+	}
+}
+
+func TestGradeOfInvalidScore(t *testing.T) {
+	if got := gradeOf(NullFloat64{}); got != GradeNone {
+		t.Errorf("gradeOf(invalid) = %q, want %q", got, GradeNone)
+	}
+}
+
+func TestFacilityScoresGrades(t *testing.T) {
+	scores := FacilityScores{
+		PCIScore: NullFloat64{sql.NullFloat64{Float64: 95, Valid: true}},
+		OFSScore: NullFloat64{sql.NullFloat64{Float64: 55, Valid: true}},
+	}
+
+	grades := scores.Grades()
+	if grades.PCI != GradeAPlus {
+		t.Errorf("PCI grade = %q, want %q", grades.PCI, GradeAPlus)
+	}
+	if grades.OFS != GradeD {
+		t.Errorf("OFS grade = %q, want %q", grades.OFS, GradeD)
+	}
+	if grades.ERI != GradeNone {
+		t.Errorf("ERI grade for unset score = %q, want %q", grades.ERI, GradeNone)
+	}
+}
+
+func TestScoredFacilityMarshalsFlat(t *testing.T) {
+	scored := ScoredFacility{
+		Facility: Facility{ID: "f1", Name: "Test Hospital"},
+		FacilityScores: FacilityScores{
+			PCIScore: NullFloat64{sql.NullFloat64{Float64: 95, Valid: true}},
+		},
+	}
+	scored.ScoreGrades = scored.FacilityScores.Grades()
+
+	raw, err := json.Marshal(scored)
+	if err != nil {
+		t.Fatalf("json.Marshal returned error: %v", err)
+	}
+
+	var out map[string]interface{}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatalf("json.Unmarshal returned error: %v", err)
+	}
+
+	for _, key := range []string{"id", "name", "pci_score", "pci_grade"} {
+		if _, ok := out[key]; !ok {
+			t.Errorf("expected flat top-level key %q in %s", key, raw)
+		}
+	}
+	if _, ok := out["scores"]; ok {
+		t.Errorf("expected no nested \"scores\" key, got %s", raw)
+	}
+	if _, ok := out["grades"]; ok {
+		t.Errorf("expected no nested \"grades\" key, got %s", raw)
+	}
+}
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SuccessResponse writes a standard success envelope around data.
+func SuccessResponse(c *gin.Context, data interface{}) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    data,
+	})
+}
+
+// ErrorResponse writes a standard error envelope.
+func ErrorResponse(c *gin.Context, status int, message, detail string) {
+	c.JSON(status, gin.H{
+		"success": false,
+		"error": gin.H{
+			"message": message,
+			"detail":  detail,
+		},
+	})
+}
+
+// PaginatedResponse writes a standard offset-paginated envelope.
+func PaginatedResponse(c *gin.Context, data interface{}, total, limit, offset int) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    data,
+		"pagination": gin.H{
+			"total":  total,
+			"limit":  limit,
+			"offset": offset,
+		},
+	})
+}
+
+// PageInfo describes the cursor position of a Connection relative to the
+// full result set, Relay-style.
+type PageInfo struct {
+	StartCursor     string `json:"startCursor,omitempty"`
+	EndCursor       string `json:"endCursor,omitempty"`
+	HasNextPage     bool   `json:"hasNextPage"`
+	HasPreviousPage bool   `json:"hasPreviousPage"`
+}
+
+// Edge pairs a node with the opaque cursor pointing at it.
+type Edge struct {
+	Cursor string      `json:"cursor"`
+	Node   interface{} `json:"node"`
+}
+
+// Connection is the Relay-style envelope for cursor-paginated list results.
+type Connection struct {
+	TotalCount int      `json:"totalCount"`
+	Edges      []Edge   `json:"edges"`
+	PageInfo   PageInfo `json:"pageInfo"`
+}
+
+// ConnectionResponse writes a Connection envelope for cursor-based pagination.
+// It is the keyset-pagination sibling of PaginatedResponse.
+func ConnectionResponse(c *gin.Context, edges []Edge, totalCount int, pageInfo PageInfo) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": Connection{
+			TotalCount: totalCount,
+			Edges:      edges,
+			PageInfo:   pageInfo,
+		},
+	})
+}
+
+// CachedResponse writes the standard success envelope around data, tagged
+// with an ETag derived from its content. If the request's If-None-Match
+// header already matches, it writes 304 Not Modified instead of the body.
+func CachedResponse(c *gin.Context, data interface{}) {
+	body := gin.H{"success": true, "data": data}
+	raw, err := json.Marshal(body)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to encode response", err.Error())
+		return
+	}
+
+	sum := sha1.Sum(raw)
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+
+	c.Header("ETag", etag)
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", raw)
+}